@@ -29,6 +29,7 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"os/user"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -40,6 +41,7 @@ import (
 	"github.com/docker/machine/libmachine/mcnutils"
 	"github.com/docker/machine/libmachine/ssh"
 	"github.com/docker/machine/libmachine/state"
+	"github.com/johanneswuerbach/nfsexports"
 	"github.com/pkg/errors"
 
 	"k8s.io/klog/v2"
@@ -55,12 +57,22 @@ import (
 )
 
 const (
-	isoFilename    = "boot2docker.iso"
-	pidFileName    = "vfkit.pid"
-	sockFilename   = "vfkit.sock"
-	logFileName    = "vfkit.log"
-	serialFileName = "serial.log"
-	defaultSSHUser = "docker"
+	isoFilename     = "boot2docker.iso"
+	seedISOFilename = "seed.iso"
+	pidFileName     = "vfkit.pid"
+	sockFilename    = "vfkit.sock"
+	logFileName     = "vfkit.log"
+	serialFileName  = "serial.log"
+	defaultSSHUser  = "docker"
+
+	// defaultNFSSharesRoot is the guest mountpoint under which NFS shares are
+	// mounted when NFSSharesRoot is not set.
+	defaultNFSSharesRoot = "/nfsshares"
+
+	// nfsExportComment is the nfsexports identifier marking the lines this
+	// driver owns in /etc/exports, so they can be found and removed later
+	// without touching other exports.
+	nfsExportComment = "minikube-vfkit %s-%s"
 )
 
 // Driver is the machine driver for vfkit (Virtualization.framework)
@@ -75,6 +87,60 @@ type Driver struct {
 	Network        string        // "", "nat", "vmnet-shared"
 	MACAddress     string        // For network=nat, network=""
 	VmnetHelper    *vmnet.Helper // For network=vmnet-shared
+
+	// NFSShare is the list of host directories to export to the guest over
+	// NFS. Requires Network to be "vmnet-shared" since vfkit's default "nat"
+	// network cannot route from the guest back to the host.
+	NFSShare []string
+	// NFSSharesRoot is the guest directory under which each entry of
+	// NFSShare is mounted, defaulting to defaultNFSSharesRoot.
+	NFSSharesRoot string
+
+	// VirtiofsMounts is the list of virtio-fs shared directories to expose
+	// to the guest. Unlike NFSShare, these work with any --network setting.
+	VirtiofsMounts []VirtiofsMount
+
+	// Rosetta enables transparent amd64 emulation via Rosetta 2 on arm64
+	// hosts. It is rejected on amd64 hosts, which have no Rosetta runtime.
+	Rosetta bool
+
+	// UserData is a path to a #cloud-config file, or inline #cloud-config
+	// text, appended to the generated cloud-init user-data so callers can
+	// inject package installs, sysctls, or systemd units at first boot.
+	// Its keys are not merged with the generated ones.
+	UserData string
+}
+
+const (
+	rosettaMountTag   = "rosetta"
+	rosettaMountPoint = "/mnt/rosetta"
+	rosettaRuntimeLib = "/Library/Apple/usr/libexec/oah/libRosettaRuntime"
+)
+
+// VirtiofsMount describes a single `--vfkit-virtiofs-mount host:tag[:guest]`
+// entry: HostDir is shared with the guest under the virtio-fs device tagged
+// Tag, which the guest mounts at GuestDir.
+type VirtiofsMount struct {
+	HostDir  string
+	Tag      string
+	GuestDir string
+}
+
+// ParseVirtiofsMount parses a `--vfkit-virtiofs-mount host:tag[:guest]` flag
+// value. When guest is omitted it defaults to host.
+func ParseVirtiofsMount(spec string) (VirtiofsMount, error) {
+	parts := strings.SplitN(spec, ":", 3)
+	if len(parts) < 2 {
+		return VirtiofsMount{}, fmt.Errorf("invalid --vfkit-virtiofs-mount %q: expected host:tag[:guest]", spec)
+	}
+	m := VirtiofsMount{HostDir: parts[0], Tag: parts[1], GuestDir: parts[0]}
+	if len(parts) == 3 {
+		m.GuestDir = parts[2]
+	}
+	if m.HostDir == "" || m.Tag == "" {
+		return VirtiofsMount{}, fmt.Errorf("invalid --vfkit-virtiofs-mount %q: host and tag are required", spec)
+	}
+	return m, nil
 }
 
 func NewDriver(hostName, storePath string) drivers.Driver {
@@ -89,6 +155,48 @@ func NewDriver(hostName, storePath string) drivers.Driver {
 }
 
 func (d *Driver) PreCreateCheck() error {
+	if len(d.NFSShare) > 0 && d.Network != "vmnet-shared" {
+		return fmt.Errorf("NFS shared folders require --network=vmnet-shared: vfkit's default %q network cannot route from the guest back to the host", "nat")
+	}
+
+	tags := map[string]bool{}
+	for _, m := range d.VirtiofsMounts {
+		if tags[m.Tag] {
+			return fmt.Errorf("duplicate --vfkit-virtiofs-mount tag %q", m.Tag)
+		}
+		tags[m.Tag] = true
+	}
+
+	if d.Rosetta {
+		if runtime.GOARCH != "arm64" {
+			return fmt.Errorf("--vfkit-rosetta is only supported on Apple Silicon (arm64) hosts")
+		}
+		if err := ensureRosettaInstalled(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureRosettaInstalled checks whether Rosetta 2 is installed and, if it's
+// missing, prints a notice and installs it via softwareupdate, accepting the
+// license non-interactively.
+func ensureRosettaInstalled() error {
+	if _, err := os.Stat(rosettaRuntimeLib); err == nil {
+		return nil
+	}
+	if err := exec.Command("arch", "-x86_64", "/usr/bin/true").Run(); err == nil {
+		return nil
+	}
+
+	out.Styled(style.Tip, "Rosetta 2 is required for --vfkit-rosetta but is not installed, installing now")
+	cmd := exec.Command("softwareupdate", "--install-rosetta", "--agree-to-license")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, "failed to install Rosetta 2")
+	}
 	return nil
 }
 
@@ -207,6 +315,11 @@ func (d *Driver) Create() error {
 		return err
 	}
 
+	log.Info("Creating cloud-init seed image...")
+	if err := d.generateCloudInitSeed(); err != nil {
+		return err
+	}
+
 	if d.ExtraDisks > 0 {
 		log.Info("Creating extra disk images...")
 		for i := 0; i < d.ExtraDisks; i++ {
@@ -221,6 +334,183 @@ func (d *Driver) Create() error {
 	return d.Start()
 }
 
+// nfsExportIdentifier returns the comment nfsexports uses to recognize and
+// later remove the /etc/exports entry it created for share.
+func (d *Driver) nfsExportIdentifier(share string) string {
+	return fmt.Sprintf(nfsExportComment, d.MachineName, share)
+}
+
+// etcExportsPath is the real /etc/exports nfsexports.Add/Remove edit when
+// passed "". We never point them at it directly: both write the file with a
+// plain os.WriteFile and no privilege elevation of their own, which fails
+// for the non-root user minikube normally runs as. Instead we let them edit
+// a scratch copy we control, then commit the result to the real file in one
+// administrator-privileged step.
+const etcExportsPath = "/etc/exports"
+
+// nfsExportsScratchCopy copies etcExportsPath (if it exists) into a temp
+// file that nfsexports.Add/Remove can edit without touching the real file.
+func nfsExportsScratchCopy() (string, error) {
+	f, err := os.CreateTemp("", "minikube-vfkit-exports")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	existing, err := os.ReadFile(etcExportsPath)
+	if err != nil && !os.IsNotExist(err) {
+		os.Remove(f.Name())
+		return "", err
+	}
+	if _, err := f.Write(existing); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// commitExportsPrivileged copies data over the real /etc/exports and
+// reloads nfsd, prompting the user for their password via a GUI
+// administrator-privileges prompt since minikube itself does not run as
+// root.
+func commitExportsPrivileged(data []byte) error {
+	tmp, err := os.CreateTemp("", "minikube-vfkit-exports")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := os.WriteFile(tmp.Name(), data, 0644); err != nil {
+		return err
+	}
+
+	script := fmt.Sprintf("cp %s %s && nfsd update", tmp.Name(), etcExportsPath)
+	cmd := exec.Command("osascript", "-e",
+		fmt.Sprintf(`do shell script %q with administrator privileges with prompt "minikube needs administrator privileges to configure NFS shared folders"`, script))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to update %s with administrator privileges: %s", etcExportsPath, out)
+	}
+	return nil
+}
+
+// addNFSShares (re-)exports each entry of d.NFSShare to the host's
+// /etc/exports, restricted to d.IPAddress so only this guest can mount them,
+// and reloads nfsd. It must run after d.IPAddress is known (i.e. from
+// Start(), not Create()), and on every boot: Stop() tears the exports back
+// down, and vfkit assigns a fresh guest IP on each Start(). Each share is
+// removed before being re-added rather than relying on nfsexports.Add's
+// idempotent-by-identifier behavior, since the identifier doesn't change
+// across restarts but the guest IP in the export line does.
+func (d *Driver) addNFSShares() error {
+	u, err := user.Current()
+	if err != nil {
+		return err
+	}
+
+	scratch, err := nfsExportsScratchCopy()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(scratch)
+
+	for _, share := range d.NFSShare {
+		id := d.nfsExportIdentifier(share)
+		if _, err := nfsexports.Remove(scratch, id); err != nil && !strings.Contains(err.Error(), "Couldn't not find export") {
+			return errors.Wrapf(err, "failed to refresh nfs share %q", share)
+		}
+
+		nfsConfig := fmt.Sprintf("%s %s -alldirs -mapall=%s", share, d.IPAddress, u.Username)
+		if _, err := nfsexports.Add(scratch, id, nfsConfig); err != nil {
+			if strings.Contains(err.Error(), "Export verification failed") {
+				log.Info("Conflicting NFS share not setup and ignored:", err)
+				continue
+			}
+			return errors.Wrapf(err, "failed to add nfs share %q", share)
+		}
+	}
+
+	data, err := os.ReadFile(scratch)
+	if err != nil {
+		return err
+	}
+	return commitExportsPrivileged(data)
+}
+
+// removeNFSShares removes every export this driver registered for its
+// machine and reloads nfsd.
+func (d *Driver) removeNFSShares() error {
+	if len(d.NFSShare) == 0 {
+		return nil
+	}
+	log.Infof("You must be root to remove NFS shared folders. Please type root password.")
+
+	scratch, err := nfsExportsScratchCopy()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(scratch)
+
+	for _, share := range d.NFSShare {
+		if _, err := nfsexports.Remove(scratch, d.nfsExportIdentifier(share)); err != nil {
+			if !strings.Contains(err.Error(), "Couldn't not find export") {
+				log.Errorf("failed removing nfs share (%s): %v", share, err)
+			}
+		}
+	}
+
+	data, err := os.ReadFile(scratch)
+	if err != nil {
+		return err
+	}
+	return commitExportsPrivileged(data)
+}
+
+// nfsSharesRoot returns the guest mountpoint under which NFS shares are
+// mounted, defaulting to defaultNFSSharesRoot.
+func (d *Driver) nfsSharesRoot() string {
+	if d.NFSSharesRoot == "" {
+		return defaultNFSSharesRoot
+	}
+	return d.NFSSharesRoot
+}
+
+// vmnetGatewayIP derives the host's address on the vmnet-shared bridge from
+// the guest's own address on that bridge: vmnet-helper assigns the host the
+// ".1" address of the guest's /24.
+func vmnetGatewayIP(guestIP string) (string, error) {
+	ip := net.ParseIP(guestIP).To4()
+	if ip == nil {
+		return "", fmt.Errorf("cannot determine vmnet subnet from guest IP %q", guestIP)
+	}
+	return fmt.Sprintf("%d.%d.%d.1", ip[0], ip[1], ip[2]), nil
+}
+
+// mountNFSShares mounts each entry of d.NFSShare from the vmnet-shared host
+// IP into d.nfsSharesRoot() inside the guest, since vfkit's "nat" network
+// cannot route back to the host.
+func (d *Driver) mountNFSShares() error {
+	if len(d.NFSShare) == 0 {
+		return nil
+	}
+	hostIP, err := vmnetGatewayIP(d.IPAddress)
+	if err != nil {
+		return errors.Wrap(err, "failed to determine host IP for NFS mount")
+	}
+
+	root := d.nfsSharesRoot()
+	var script strings.Builder
+	script.WriteString("set -e\n")
+	for _, share := range d.NFSShare {
+		guestDir := filepath.Join(root, share)
+		fmt.Fprintf(&script, "sudo mkdir -p %q\n", guestDir)
+		fmt.Fprintf(&script, "sudo mount -t nfs -o noacl,async %s:%s %q\n", hostIP, share, guestDir)
+	}
+
+	if _, err := drivers.RunSSHCommandFromDriver(d, script.String()); err != nil {
+		return errors.Wrap(err, "failed to mount nfs shares")
+	}
+	return nil
+}
+
 func (d *Driver) extractKernel() error {
 	log.Info("Extracting bzimage and initrd...")
 	isoPath := d.ResolveStorePath(isoFilename)
@@ -250,9 +540,99 @@ func (d *Driver) Start() error {
 		return err
 	}
 
+	if len(d.NFSShare) > 0 {
+		log.Info("Setting up NFS shared folders...")
+		if err := d.addNFSShares(); err != nil {
+			return err
+		}
+	}
+
 	log.Infof("Waiting for VM to start (ssh -p %d docker@%s)...", d.SSHPort, d.IPAddress)
 
-	return WaitForTCPWithDelay(fmt.Sprintf("%s:%d", d.IPAddress, d.SSHPort), time.Second)
+	if err := WaitForTCP(context.Background(), fmt.Sprintf("%s:%d", d.IPAddress, d.SSHPort), time.Second); err != nil {
+		return err
+	}
+
+	if err := d.mountVirtiofsShares(); err != nil {
+		return err
+	}
+
+	if err := d.registerRosetta(); err != nil {
+		return err
+	}
+
+	return d.mountNFSShares()
+}
+
+// registerRosetta mounts the rosetta virtio-fs share and registers it as a
+// binfmt_misc interpreter for amd64 ELF binaries, so `docker run
+// --platform=linux/amd64` runs transparently under Rosetta 2. See
+// https://docs.kernel.org/admin-guide/binfmt-misc.html.
+func (d *Driver) registerRosetta() error {
+	if !d.Rosetta {
+		return nil
+	}
+
+	binfmt := strings.Join([]string{
+		// name
+		":rosetta",
+		// type: M (magic number matching)
+		":M",
+		// offset (default 0)
+		":",
+		// magic: x86_64 ELF header
+		`:\x7fELF\x02\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x02\x00\x3e\x00`,
+		// mask
+		`:\xff\xff\xff\xff\xff\xfe\xfe\x00\xff\xff\xff\xff\xff\xff\xff\xff\xfe\xff\xff\xff`,
+		// interpreter
+		":" + filepath.Join(rosettaMountPoint, "rosetta"),
+		// flags: F (fix binary)
+		":F",
+	}, "")
+
+	mountCmd := fmt.Sprintf("sudo mkdir -p %s && sudo mount -t virtiofs %s %s", rosettaMountPoint, rosettaMountTag, rosettaMountPoint)
+	if _, err := drivers.RunSSHCommandFromDriver(d, mountCmd); err != nil {
+		return fmt.Errorf("failed to mount rosetta virtiofs share: %w", err)
+	}
+
+	// Tolerate binfmt_misc already being mounted: some boot2docker kernels
+	// mount it automatically at boot, and run as a separate command (instead
+	// of chaining it onto the mount command above with "&&") so that expected
+	// failure doesn't also abort the register step below.
+	if _, err := drivers.RunSSHCommandFromDriver(d, "sudo mount -t binfmt_misc binfmt_misc /proc/sys/fs/binfmt_misc"); err != nil {
+		if !strings.Contains(err.Error(), "already mounted") {
+			return fmt.Errorf("failed to mount binfmt_misc: %w", err)
+		}
+		log.Debugf("binfmt_misc already mounted, continuing")
+	}
+
+	registerCmd := fmt.Sprintf("echo '%s' | sudo tee /proc/sys/fs/binfmt_misc/register", binfmt)
+	if _, err := drivers.RunSSHCommandFromDriver(d, registerCmd); err != nil {
+		// Tolerate the rosetta interpreter already being registered, e.g. by
+		// a previous Start() of the same running VM.
+		if !strings.Contains(err.Error(), "File exists") {
+			return fmt.Errorf("failed to register Rosetta binfmt_misc handler: %w", err)
+		}
+		log.Debugf("rosetta binfmt_misc handler already registered, continuing")
+	}
+	return nil
+}
+
+// mountVirtiofsShares mounts each configured virtio-fs device into the guest
+// at its GuestDir, creating the mountpoint first. If the guest kernel lacks
+// virtiofs support, it warns and continues instead of failing Start().
+func (d *Driver) mountVirtiofsShares() error {
+	for _, m := range d.VirtiofsMounts {
+		cmd := fmt.Sprintf("sudo mkdir -p %s && sudo mount -t virtiofs %s %s", m.GuestDir, m.Tag, m.GuestDir)
+		if _, err := drivers.RunSSHCommandFromDriver(d, cmd); err != nil {
+			if strings.Contains(err.Error(), "unknown filesystem type 'virtiofs'") {
+				log.Warnf("guest kernel does not support virtiofs, skipping mount of %q", m.HostDir)
+				continue
+			}
+			return errors.Wrapf(err, "failed to mount virtiofs share %q", m.HostDir)
+		}
+	}
+	return nil
 }
 
 // startVfkit starts the vfkit child process. If socketPath is not empty, vfkit
@@ -296,6 +676,16 @@ func (d *Driver) startVfkit(socketPath string) error {
 	startCmd = append(startCmd,
 		"--device", "virtio-rng")
 
+	for _, m := range d.VirtiofsMounts {
+		startCmd = append(startCmd,
+			"--device", fmt.Sprintf("virtio-fs,sharedDir=%s,mountTag=%s", m.HostDir, m.Tag))
+	}
+
+	if d.Rosetta {
+		startCmd = append(startCmd,
+			"--device", fmt.Sprintf("rosetta,mountTag=%s", rosettaMountTag))
+	}
+
 	var isoPath = filepath.Join(machineDir, isoFilename)
 	startCmd = append(startCmd,
 		"--device", fmt.Sprintf("virtio-blk,path=%s", isoPath))
@@ -308,6 +698,17 @@ func (d *Driver) startVfkit(socketPath string) error {
 			"--device", fmt.Sprintf("virtio-blk,path=%s", pkgdrivers.ExtraDiskPath(d.BaseDriver, i)))
 	}
 
+	// Appended after disk.img and the extra disks, not spliced between them,
+	// so existing profiles' extra-disk device enumeration order doesn't shift.
+	// Skip the device entirely for profiles created before the cloud-init
+	// seed was introduced: generateCloudInitSeed only runs from Create(), so
+	// Start() on an existing profile would otherwise point vfkit at a
+	// seed.iso that was never generated.
+	if _, err := os.Stat(d.seedPath()); err == nil {
+		startCmd = append(startCmd,
+			"--device", fmt.Sprintf("virtio-blk,path=%s", d.seedPath()))
+	}
+
 	serialPath := d.ResolveStorePath(serialFileName)
 	startCmd = append(startCmd,
 		"--device", fmt.Sprintf("virtio-serial,logFilePath=%s", serialPath))
@@ -420,6 +821,9 @@ func (d *Driver) Stop() error {
 	if err := d.stopVfkit(); err != nil {
 		return err
 	}
+	if err := d.removeNFSShares(); err != nil {
+		log.Debugf("failed to remove nfs shares: %s", err)
+	}
 	return d.stopVmnetHelper()
 }
 
@@ -433,6 +837,9 @@ func (d *Driver) Remove() error {
 			return errors.Wrap(err, "kill")
 		}
 	}
+	if err := d.removeNFSShares(); err != nil {
+		log.Debugf("failed to remove nfs shares: %s", err)
+	}
 	return nil
 }
 
@@ -593,65 +1000,361 @@ func (d *Driver) generateDiskImage(size int) error {
 	return nil
 }
 
-func httpUnixClient(path string) http.Client {
-	return http.Client{
-		Transport: &http.Transport{
-			DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
-				return net.Dial("unix", path)
+// generateCloudInitSeed writes a cloud-init NoCloud user-data/meta-data pair
+// and packs them into an ISO9660 image labeled "cidata" next to disk.img,
+// the volume label and filename layout cloud-init's NoCloud datasource
+// looks for on an attached block device.
+//
+// This only takes effect if the boot2docker guest image actually runs
+// cloud-init on first boot; unlike the NFS, virtiofs, and Rosetta setup in
+// this driver, which all run over post-boot SSH, that has not been verified
+// against the ISO minikube ships. If it doesn't, d.UserData is silently
+// never applied.
+func (d *Driver) generateCloudInitSeed() error {
+	userData, err := d.cloudInitUserData()
+	if err != nil {
+		return err
+	}
+	metaData := fmt.Sprintf("instance-id: %s\nlocal-hostname: %s\n", d.MachineName, d.MachineName)
+
+	seedDir, err := os.MkdirTemp("", "minikube-cidata")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(seedDir)
+
+	if err := os.WriteFile(filepath.Join(seedDir, "user-data"), []byte(userData), 0644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(seedDir, "meta-data"), []byte(metaData), 0644); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("hdiutil", "makehybrid", "-iso", "-joliet",
+		"-default-volume-name", "cidata", "-o", d.seedPath(), seedDir)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "failed to create cloud-init seed image: %s", out)
+	}
+	return nil
+}
+
+// cloudInitUserData builds the #cloud-config user-data: the SSH key written
+// by Create(), the machine's hostname, and, if set, d.UserData appended so
+// callers can layer on their own package installs, sysctls, or systemd
+// units. d.UserData may be a path to a #cloud-config file or inline text.
+// The appended text is added as-is (minus a leading "#cloud-config" line);
+// top-level keys it shares with the generated part are not merged.
+func (d *Driver) cloudInitUserData() (string, error) {
+	pubKey, err := os.ReadFile(d.publicSSHKeyPath())
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("#cloud-config\n")
+	fmt.Fprintf(&buf, "hostname: %s\n", d.MachineName)
+	buf.WriteString("ssh_authorized_keys:\n")
+	fmt.Fprintf(&buf, "  - %s", pubKey)
+
+	if d.UserData == "" {
+		return buf.String(), nil
+	}
+
+	extra := d.UserData
+	if data, err := os.ReadFile(d.UserData); err == nil {
+		extra = string(data)
+	}
+	buf.WriteString("\n")
+	buf.WriteString(strings.TrimPrefix(extra, "#cloud-config\n"))
+	return buf.String(), nil
+}
+
+// seedPath returns the path of the cloud-init seed ISO attached to the VM.
+func (d *Driver) seedPath() string {
+	return d.ResolveStorePath(seedISOFilename)
+}
+
+// vfkitClient is a typed client for vfkit's RESTful API, reached over the
+// unix socket passed to vfkit via --restful-uri.
+type vfkitClient struct {
+	httpc   http.Client
+	timeout time.Duration
+}
+
+func newVfkitClient(sockPath string) *vfkitClient {
+	return &vfkitClient{
+		httpc: http.Client{
+			Transport: &http.Transport{
+				DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
+					return net.Dial("unix", sockPath)
+				},
 			},
 		},
+		timeout: 5 * time.Second,
 	}
 }
 
+// VMState is the JSON body of vfkit's GET/POST /vm/state endpoint.
 type VMState struct {
 	State string `json:"state"`
 }
 
-func (d *Driver) GetVFKitState() (string, error) {
-	httpc := httpUnixClient(d.sockfilePath())
-	var vmstate VMState
-	response, err := httpc.Get("http://_/vm/state")
-	if err != nil {
+// vmSnapshot is the JSON body of vfkit's POST /vm/save and /vm/restore
+// endpoints.
+type vmSnapshot struct {
+	SnapshotPath string `json:"snapshotPath"`
+}
+
+// VMInfo is the JSON body of vfkit's GET /vm/inspect endpoint.
+type VMInfo struct {
+	CPUs   uint  `json:"vcpus"`
+	Memory int64 `json:"memoryBytes"`
+}
+
+// do performs an HTTP request against vfkit's REST API, marshaling body (if
+// any) as JSON and decoding the response into out (if any). vfkit can return
+// a bare EOF while the VM is mid-transition, see
+// https://github.com/crc-org/vfkit/issues/277, so do retries once after a
+// short delay before giving up.
+func (c *vfkitClient) do(method, path string, body, out interface{}) error {
+	var reqBody []byte
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = data
+	}
+
+	const retries = 2
+	var lastErr error
+	for attempt := 0; attempt < retries; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		req, err := http.NewRequestWithContext(ctx, method, "http://_"+path, bytes.NewReader(reqBody))
+		if err != nil {
+			cancel()
+			return err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.httpc.Do(req)
+		cancel()
+		if err != nil {
+			if strings.Contains(err.Error(), "EOF") {
+				lastErr = err
+				log.Debugf("vfkit API %s %s returned EOF, retrying", method, path)
+				time.Sleep(500 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("vfkit API %s %s returned %s", method, path, resp.Status)
+		}
+		if out == nil {
+			return nil
+		}
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return lastErr
+}
+
+func (c *vfkitClient) getState() (string, error) {
+	var s VMState
+	if err := c.do(http.MethodGet, "/vm/state", nil, &s); err != nil {
 		return "", err
 	}
-	defer response.Body.Close()
-	err = json.NewDecoder(response.Body).Decode(&vmstate)
+	return s.State, nil
+}
+
+func (c *vfkitClient) setState(s string) error {
+	return c.do(http.MethodPost, "/vm/state", VMState{State: s}, nil)
+}
+
+// save and restore call endpoints not yet part of a released vfkit: as of
+// crc-org/vfkit v0.6.4 only /vm/state and /vm/inspect exist. They are wired
+// up so Driver.Snapshot/RestoreSnapshot work as soon as vfkit grows them;
+// until then they return a 404 from c.do.
+func (c *vfkitClient) save(path string) error {
+	return c.do(http.MethodPost, "/vm/save", vmSnapshot{SnapshotPath: path}, nil)
+}
+
+func (c *vfkitClient) restore(path string) error {
+	return c.do(http.MethodPost, "/vm/restore", vmSnapshot{SnapshotPath: path}, nil)
+}
+
+func (c *vfkitClient) inspect() (VMInfo, error) {
+	var info VMInfo
+	err := c.do(http.MethodGet, "/vm/inspect", nil, &info)
+	return info, err
+}
+
+func (d *Driver) vfkitClient() *vfkitClient {
+	return newVfkitClient(d.sockfilePath())
+}
+
+func (d *Driver) GetVFKitState() (string, error) {
+	s, err := d.vfkitClient().getState()
 	if err != nil {
 		return "", err
 	}
-	log.Debugf("get state: %+v", vmstate)
-	return vmstate.State, nil
+	log.Debugf("get state: %+v", s)
+	return s, nil
 }
 
 // SetVFKitState sets the state of the vfkit VM, (s is the state)
 func (d *Driver) SetVFKitState(s string) error {
-	httpc := httpUnixClient(d.sockfilePath())
-	var vmstate VMState
-	vmstate.State = s
-	data, err := json.Marshal(&vmstate)
-	if err != nil {
-		return err
-	}
-	_, err = httpc.Post("http://_/vm/state", "application/json", bytes.NewReader(data))
-	if err != nil {
+	if err := d.vfkitClient().setState(s); err != nil {
 		return err
 	}
-	log.Infof("Set vfkit state: %+v", vmstate)
+	log.Infof("Set vfkit state: %s", s)
 	return nil
 }
 
-func WaitForTCPWithDelay(addr string, duration time.Duration) error {
+// Pause suspends the running VM in place via vfkit's REST API, without an
+// SSH round-trip into the guest.
+func (d *Driver) Pause() error {
+	return d.SetVFKitState("Pause")
+}
+
+// Resume resumes a VM previously suspended with Pause.
+func (d *Driver) Resume() error {
+	return d.SetVFKitState("Resume")
+}
+
+// snapshotPath returns the path of the named snapshot file under the
+// machine directory.
+func (d *Driver) snapshotPath(name string) string {
+	return d.ResolveStorePath(fmt.Sprintf("%s.snapshot", name))
+}
+
+// Snapshot saves the running VM's state to a named snapshot file under the
+// machine directory, so RestoreSnapshot can roll back to it later.
+func (d *Driver) Snapshot(name string) error {
+	return d.vfkitClient().save(d.snapshotPath(name))
+}
+
+// RestoreSnapshot restores the VM to the state saved by an earlier call to
+// Snapshot with the same name.
+func (d *Driver) RestoreSnapshot(name string) error {
+	path := d.snapshotPath(name)
+	if _, err := os.Stat(path); err != nil {
+		return errors.Wrapf(err, "snapshot %q not found", name)
+	}
+	return d.vfkitClient().restore(path)
+}
+
+// InspectVM returns the running VM's current CPU and memory configuration.
+func (d *Driver) InspectVM() (VMInfo, error) {
+	return d.vfkitClient().inspect()
+}
+
+const (
+	// defaultWaitForTCPTimeout bounds how long WaitForTCP waits overall,
+	// so a VM that never comes up fails Start() instead of hanging it.
+	defaultWaitForTCPTimeout = 5 * time.Minute
+	// defaultWaitForTCPMaxBackoff caps the exponential backoff between
+	// dial attempts.
+	defaultWaitForTCPMaxBackoff = 10 * time.Second
+	// defaultWaitForTCPStableAttempts is the number of consecutive
+	// successful dial+read cycles required before declaring addr ready.
+	defaultWaitForTCPStableAttempts = 3
+)
+
+// Option configures WaitForTCP.
+type Option func(*waitForTCPConfig)
+
+type waitForTCPConfig struct {
+	timeout        time.Duration
+	maxBackoff     time.Duration
+	stableAttempts int
+}
+
+// WithTimeout overrides WaitForTCP's default 5 minute overall deadline.
+func WithTimeout(d time.Duration) Option {
+	return func(c *waitForTCPConfig) { c.timeout = d }
+}
+
+// WithMaxBackoff overrides the cap on the exponential backoff between dial
+// attempts.
+func WithMaxBackoff(d time.Duration) Option {
+	return func(c *waitForTCPConfig) { c.maxBackoff = d }
+}
+
+// WithStableAttempts overrides the number of consecutive successful
+// dial+read cycles required before WaitForTCP declares addr ready, guarding
+// against racing a half-open listener.
+func WithStableAttempts(n int) Option {
+	return func(c *waitForTCPConfig) { c.stableAttempts = n }
+}
+
+// WaitForTCP waits for addr to start accepting TCP connections, the way
+// docker-machine's "no active transaction" check waits for a service to
+// stabilize: it requires several consecutive successful dial+read cycles
+// before declaring addr ready. Successful cycles are spaced initialBackoff
+// apart; after a failed attempt it backs off exponentially, starting at
+// initialBackoff and capped at the configured max. It gives up once ctx is
+// done or the overall timeout elapses, returning an error that wraps the
+// last dial or read error (or ctx's error, if the deadline hits between
+// successful cycles) rather than hanging forever.
+func WaitForTCP(ctx context.Context, addr string, initialBackoff time.Duration, opts ...Option) error {
+	cfg := waitForTCPConfig{
+		timeout:        defaultWaitForTCPTimeout,
+		maxBackoff:     defaultWaitForTCPMaxBackoff,
+		stableAttempts: defaultWaitForTCPStableAttempts,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	defer cancel()
+
+	backoff := initialBackoff
+	stable := 0
+	var lastErr error
+	var dialer net.Dialer
 	for {
-		conn, err := net.Dial("tcp", addr)
-		if err != nil {
-			continue
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			// A successful dial doesn't mean the remote is actually alive: if
+			// it accepts the connection but never writes or closes it, Read
+			// blocks forever since ctx only bounds DialContext, not Read. Tie
+			// the read to ctx's deadline too, so a half-open listener fails
+			// this attempt instead of hanging past the overall timeout.
+			if deadline, ok := ctx.Deadline(); ok {
+				conn.SetReadDeadline(deadline)
+			}
+			_, err = conn.Read(make([]byte, 1))
+			conn.Close()
 		}
-		defer conn.Close()
-		if _, err := conn.Read(make([]byte, 1)); err != nil && err != io.EOF {
-			time.Sleep(duration)
+		if err == nil || err == io.EOF {
+			stable++
+			if stable >= cfg.stableAttempts {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("timed out waiting for %s to stabilize: %w", addr, ctx.Err())
+			case <-time.After(initialBackoff):
+			}
 			continue
 		}
-		break
+
+		lastErr = err
+		stable = 0
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for %s to accept connections: %w", addr, lastErr)
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > cfg.maxBackoff {
+			backoff = cfg.maxBackoff
+		}
 	}
-	return nil
 }
@@ -0,0 +1,79 @@
+//go:build darwin
+
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfkit
+
+import "testing"
+
+func TestParseVirtiofsMount(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    VirtiofsMount
+		wantErr bool
+	}{
+		{
+			name: "host and tag only defaults guest to host",
+			spec: "/Users/me/src:src",
+			want: VirtiofsMount{HostDir: "/Users/me/src", Tag: "src", GuestDir: "/Users/me/src"},
+		},
+		{
+			name: "host, tag, and guest",
+			spec: "/Users/me/src:src:/mnt/src",
+			want: VirtiofsMount{HostDir: "/Users/me/src", Tag: "src", GuestDir: "/mnt/src"},
+		},
+		{
+			name:    "missing tag",
+			spec:    "/Users/me/src",
+			wantErr: true,
+		},
+		{
+			name:    "empty host",
+			spec:    ":src",
+			wantErr: true,
+		},
+		{
+			name:    "empty tag",
+			spec:    "/Users/me/src:",
+			wantErr: true,
+		},
+		{
+			name:    "empty spec",
+			spec:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVirtiofsMount(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseVirtiofsMount(%q) = %+v, want error", tt.spec, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseVirtiofsMount(%q) returned unexpected error: %v", tt.spec, err)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseVirtiofsMount(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+//go:build darwin
+
+/*
+Copyright 2024 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfkit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// acceptAndClose runs a listener that accepts every connection and closes it
+// immediately, simulating a server that is up and reachable.
+func acceptAndClose(t *testing.T, ln net.Listener) {
+	t.Helper()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+}
+
+func TestWaitForTCPSucceedsOnceStable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	acceptAndClose(t, ln)
+
+	err = WaitForTCP(context.Background(), ln.Addr().String(), 10*time.Millisecond,
+		WithTimeout(2*time.Second), WithStableAttempts(3))
+	if err != nil {
+		t.Fatalf("WaitForTCP returned unexpected error: %v", err)
+	}
+}
+
+func TestWaitForTCPTimesOutWhenNothingListens(t *testing.T) {
+	// Bind and immediately close to get a port nothing is listening on.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	start := time.Now()
+	timeout := 300 * time.Millisecond
+	err = WaitForTCP(context.Background(), addr, 20*time.Millisecond,
+		WithTimeout(timeout), WithMaxBackoff(50*time.Millisecond))
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("WaitForTCP returned nil, want a timeout error")
+	}
+	if elapsed > timeout+500*time.Millisecond {
+		t.Fatalf("WaitForTCP took %s, want bounded by timeout %s", elapsed, timeout)
+	}
+}
+
+func TestWaitForTCPBoundsHalfOpenConnection(t *testing.T) {
+	// A listener that accepts but never writes or closes: the TCP handshake
+	// succeeds but nothing is ever readable, the exact "half-open listener"
+	// case WaitForTCP must not hang against.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	release := make(chan struct{})
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		<-release // hold the connection open until the test is done with it
+		conn.Close()
+	}()
+
+	start := time.Now()
+	timeout := 300 * time.Millisecond
+	err = WaitForTCP(context.Background(), ln.Addr().String(), 20*time.Millisecond,
+		WithTimeout(timeout), WithMaxBackoff(50*time.Millisecond))
+	elapsed := time.Since(start)
+
+	close(release)
+
+	if err == nil {
+		t.Fatal("WaitForTCP returned nil against a half-open listener, want a timeout error")
+	}
+	if elapsed > timeout+500*time.Millisecond {
+		t.Fatalf("WaitForTCP took %s to bail on a half-open connection, want bounded by timeout %s", elapsed, timeout)
+	}
+}
+
+func TestWaitForTCPRespectsCallerContext(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = WaitForTCP(ctx, addr, 20*time.Millisecond, WithTimeout(time.Hour))
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("WaitForTCP returned nil, want an error from the caller's canceled context")
+	}
+	if elapsed > 2*time.Second {
+		t.Fatalf("WaitForTCP took %s despite a 100ms caller context, want it bounded by the caller's deadline", elapsed)
+	}
+}